@@ -0,0 +1,200 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Connection is the subset of cluster access Pod needs to stream logs,
+// exec/attach into a container, and reach the kubelet stats/summary proxy.
+type Connection interface {
+	DialOrDie() kubernetes.Interface
+	RestConfig() (*rest.Config, error)
+}
+
+// Executor and TerminalSizeQueue are re-exported from client-go's
+// remotecommand package so callers outside this package (namely
+// internal/resource) never need to import client-go directly.
+type (
+	Executor          = remotecommand.Executor
+	TerminalSizeQueue = remotecommand.TerminalSizeQueue
+)
+
+// Execable is implemented by resources that support interactive exec/attach
+// sessions, mirroring Loggable.
+type Execable interface {
+	Exec(ctx context.Context, ns, n, co string, cmd []string, tty bool) (Executor, error)
+	Attach(ctx context.Context, ns, n, co string, tty bool) (Executor, error)
+}
+
+// LogOptions configures a PodLogOptions request.
+type LogOptions struct {
+	SinceSeconds *int64
+	SinceTime    *time.Time
+	Timestamps   bool
+	Follow       bool
+	Previous     bool
+	TailLines    *int64
+	LimitBytes   *int64
+}
+
+// Loggable is implemented by resources that can tail their own logs.
+type Loggable interface {
+	Logs(ns, n, co string, opts LogOptions) *rest.Request
+}
+
+// Statsable is implemented by resources that can reach the kubelet
+// stats/summary proxy for network and filesystem usage beyond what the
+// metrics server reports.
+type Statsable interface {
+	ContainerSummary(node, ns, pod, co string) (rxBytes, txBytes, fsUsedBytes uint64, err error)
+}
+
+// statsSummary is the subset of the kubelet /stats/summary payload
+// ContainerSummary cares about.
+type statsSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Containers []struct {
+			Name    string `json:"name"`
+			Network *struct {
+				RxBytes uint64 `json:"rxBytes"`
+				TxBytes uint64 `json:"txBytes"`
+			} `json:"network"`
+			Rootfs *struct {
+				UsedBytes uint64 `json:"usedBytes"`
+			} `json:"rootfs"`
+		} `json:"containers"`
+	} `json:"pods"`
+}
+
+// Pod represents a Kubernetes pod at the client-go layer.
+type Pod struct {
+	Connection
+}
+
+// NewPod returns a new pod accessor bound to the given connection.
+func NewPod(c Connection) *Pod {
+	return &Pod{Connection: c}
+}
+
+// Logs returns a request streaming the given container's logs per opts.
+func (p *Pod) Logs(ns, n, co string, opts LogOptions) *rest.Request {
+	popts := &v1.PodLogOptions{
+		Container:    co,
+		Follow:       opts.Follow,
+		Previous:     opts.Previous,
+		Timestamps:   opts.Timestamps,
+		SinceSeconds: opts.SinceSeconds,
+		TailLines:    opts.TailLines,
+		LimitBytes:   opts.LimitBytes,
+	}
+	if opts.SinceTime != nil {
+		t := metav1.NewTime(*opts.SinceTime)
+		popts.SinceTime = &t
+	}
+
+	return p.DialOrDie().CoreV1().Pods(ns).GetLogs(n, popts)
+}
+
+// ContainerSummary fetches a container's network and filesystem usage from
+// the kubelet stats/summary proxy, for the figures the metrics server
+// doesn't report.
+func (p *Pod) ContainerSummary(node, ns, pod, co string) (rxBytes, txBytes, fsUsedBytes uint64, err error) {
+	raw, err := p.DialOrDie().CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(node).
+		SubResource("proxy").
+		Suffix("stats/summary").
+		DoRaw(context.Background())
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("stats/summary proxy unreachable for node %s: %w", node, err)
+	}
+
+	var summary statsSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid stats/summary payload for node %s: %w", node, err)
+	}
+
+	for _, sp := range summary.Pods {
+		if sp.PodRef.Namespace != ns || sp.PodRef.Name != pod {
+			continue
+		}
+		for _, sc := range sp.Containers {
+			if sc.Name != co {
+				continue
+			}
+			if sc.Network != nil {
+				rxBytes, txBytes = sc.Network.RxBytes, sc.Network.TxBytes
+			}
+			if sc.Rootfs != nil {
+				fsUsedBytes = sc.Rootfs.UsedBytes
+			}
+			return rxBytes, txBytes, fsUsedBytes, nil
+		}
+	}
+
+	return 0, 0, 0, fmt.Errorf("no stats found for %s/%s:%s on node %s", ns, pod, co, node)
+}
+
+// Exec starts an interactive exec session in the given container over a
+// SPDY-negotiated stream.
+func (p *Pod) Exec(ctx context.Context, ns, n, co string, cmd []string, tty bool) (Executor, error) {
+	cfg, err := p.RestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("no rest config for %s/%s:%s: %w", ns, n, co, err)
+	}
+
+	req := p.DialOrDie().CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(n).
+		Namespace(ns).
+		SubResource("exec").
+		Context(ctx).
+		VersionedParams(&v1.PodExecOptions{
+			Container: co,
+			Command:   cmd,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
+			TTY:       tty,
+		}, scheme.ParameterCodec)
+
+	return remotecommand.NewSPDYExecutor(cfg, "POST", req.URL())
+}
+
+// Attach joins an already running process in the given container.
+func (p *Pod) Attach(ctx context.Context, ns, n, co string, tty bool) (Executor, error) {
+	cfg, err := p.RestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("no rest config for %s/%s:%s: %w", ns, n, co, err)
+	}
+
+	req := p.DialOrDie().CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(n).
+		Namespace(ns).
+		SubResource("attach").
+		Context(ctx).
+		VersionedParams(&v1.PodAttachOptions{
+			Container: co,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    true,
+			TTY:       tty,
+		}, scheme.ParameterCodec)
+
+	return remotecommand.NewSPDYExecutor(cfg, "POST", req.URL())
+}
@@ -3,7 +3,11 @@ package resource
 import (
 	"bufio"
 	"context"
+	"fmt"
+	"io"
+	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,13 +24,20 @@ type (
 
 		pod           *v1.Pod
 		isInit        bool
+		isAll         bool
 		instance      *v1.Container
 		MetricsServer MetricsServer
 		metrics       *mv1beta1.PodMetrics
+		filters       []ContainerFilter
 		mx            sync.RWMutex
 	}
 )
 
+// AllContainers is the pseudo container name whose Logs fan in every
+// container of the pod, init and regular alike, analogous to
+// `kubectl logs -f --all-containers --prefix`.
+const AllContainers = "--all"
+
 // NewContainerList returns a collection of container.
 func NewContainerList(c Connection, mx MetricsServer, pod *v1.Pod) List {
 	return NewList(
@@ -69,63 +80,690 @@ func (r *Container) Marshal(path string) (string, error) {
 	return "", nil
 }
 
-// Logs tails a given container logs
-func (r *Container) Logs(c chan<- string, ns, n, co string, lines int64, prev bool) (context.CancelFunc, error) {
-	req := r.Resource.(k8s.Loggable).Logs(ns, n, co, lines, prev)
+// LogOptions configures a container log tail, mirroring the knobs exposed by
+// PodLogOptions plus a client-side Grep filter applied to each scanned line.
+type LogOptions struct {
+	SinceSeconds *int64
+	SinceTime    *time.Time
+	Timestamps   bool
+	Follow       bool
+	Previous     bool
+	TailLines    *int64
+	LimitBytes   *int64
+	Grep         string
+}
+
+// Logs tails a given container logs per the supplied LogOptions. When Follow
+// is set, a stream the API server closes mid-tail (log rotation, container
+// restart) is transparently re-established and a synthetic marker line is
+// sent on the channel to flag the gap; the reconnect resumes from the
+// timestamp of the last line read instead of re-tailing the whole history.
+func (r *Container) Logs(c chan<- string, ns, n, co string, opts LogOptions) (context.CancelFunc, error) {
+	if r.isAll {
+		return r.logsAll(c, ns, n, opts)
+	}
+
 	ctx, cancel := context.WithCancel(context.TODO())
-	req.Context(ctx)
 
-	blocked := true
+	var grep *regexp.Regexp
+	if opts.Grep != "" {
+		re, err := regexp.Compile(opts.Grep)
+		if err != nil {
+			cancel()
+			return cancel, err
+		}
+		grep = re
+	}
+
 	go func() {
-		select {
-		case <-time.After(defaultTimeout):
-			var closes bool
-			r.mx.RLock()
-			{
-				closes = blocked
+		defer func() {
+			log.Debug().Msg("!!!Closing Stream!!!")
+			close(c)
+			cancel()
+		}()
+
+		for {
+			stream, err := r.openLogStream(ctx, ns, n, co, opts)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Stream canceled `%s/%s:%s", ns, n, co)
+				return
+			}
+
+			last, clean := r.drainLogStream(ctx, stream, c, grep, opts.Timestamps)
+			if !clean || !opts.Follow {
+				return
 			}
-			r.mx.RUnlock()
-			if closes {
-				log.Debug().Msg(">>Closing Channel<<")
-				close(c)
-				cancel()
+
+			if !last.IsZero() {
+				// Resume just past the last line read rather than replaying
+				// the whole history on reconnect; SinceSeconds/TailLines
+				// would otherwise fight with SinceTime over where the tail
+				// starts, so they're dropped in favor of it.
+				since := last.Add(time.Nanosecond)
+				opts.SinceTime = &since
+				opts.SinceSeconds = nil
+				opts.TailLines = nil
 			}
+
+			log.Debug().Msgf("Reconnecting log stream `%s/%s:%s", ns, n, co)
+			c <- fmt.Sprintf("…stream to %s/%s:%s lost, reconnecting…", ns, n, co)
 		}
 	}()
 
-	// This call will block if nothing is in the stream!!
-	stream, err := req.Stream()
-	if err != nil {
-		log.Warn().Err(err).Msgf("Stream canceled `%s/%s:%s", ns, n, co)
-		return cancel, err
+	return cancel, nil
+}
+
+// logsAll fans in the logs of every container in the pod, init and regular
+// alike, prefixing each line with its originating container name. The
+// aggregate channel is closed once every sub-stream has ended or the
+// returned CancelFunc fires.
+func (r *Container) logsAll(c chan<- string, ns, n string, opts LogOptions) (context.CancelFunc, error) {
+	names := make([]string, 0, len(r.pod.Spec.InitContainers)+len(r.pod.Spec.Containers))
+	for _, co := range r.pod.Spec.InitContainers {
+		names = append(names, co.Name)
+	}
+	for _, co := range r.pod.Spec.Containers {
+		names = append(names, co.Name)
+	}
+
+	open := func(co string) (<-chan string, context.CancelFunc, error) {
+		sub := make(chan string)
+		subCancel, err := NewContainer(r.Connection, r.MetricsServer, r.pod).Logs(sub, ns, n, co, opts)
+		return sub, subCancel, err
 	}
 
-	r.mx.Lock()
-	{
-		blocked = false
+	return fanInLogs(names, open, c)
+}
+
+// fanInLogs multiplexes the per-container line sources open returns into c,
+// prefixing each line with "[container]". It closes c once every sub-stream
+// has ended or the returned CancelFunc fires; a container whose stream
+// fails to open is logged and skipped rather than failing the whole fan-in.
+func fanInLogs(names []string, open func(co string) (<-chan string, context.CancelFunc, error), c chan<- string) (context.CancelFunc, error) {
+	ctx, cancel := context.WithCancel(context.TODO())
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		sub, subCancel, err := open(name)
+		if err != nil {
+			log.Warn().Err(err).Msgf("Log fan-in skipped for %q", name)
+			continue
+		}
+
+		wg.Add(1)
+		go func(co string, subCancel context.CancelFunc) {
+			defer wg.Done()
+			go func() {
+				<-ctx.Done()
+				subCancel()
+			}()
+
+			for line := range sub {
+				select {
+				case c <- fmt.Sprintf("[%s] %s", co, line):
+				case <-ctx.Done():
+				}
+			}
+		}(name, subCancel)
 	}
-	r.mx.Unlock()
 
 	go func() {
-		defer func() {
-			log.Debug().Msg("!!!Closing Stream!!!")
-			close(c)
-			stream.Close()
-			cancel()
+		wg.Wait()
+		close(c)
+	}()
+
+	return cancel, nil
+}
+
+// openLogStream negotiates the log request and blocks only until the first
+// byte is available, bounded by defaultTimeout. The returned stream is then
+// read without any further deadline, so a long but healthy tail is never cut
+// short by the connection-setup timeout. Timestamps are always requested of
+// the API server regardless of opts.Timestamps, so drainLogStream can track
+// the last line read for a reconnect to resume from; it strips them back out
+// unless the caller actually asked to see them.
+func (r *Container) openLogStream(ctx context.Context, ns, n, co string, opts LogOptions) (io.ReadCloser, error) {
+	req := r.Resource.(k8s.Loggable).Logs(ns, n, co, k8s.LogOptions{
+		SinceSeconds: opts.SinceSeconds,
+		SinceTime:    opts.SinceTime,
+		Timestamps:   true,
+		Follow:       opts.Follow,
+		Previous:     opts.Previous,
+		TailLines:    opts.TailLines,
+		LimitBytes:   opts.LimitBytes,
+	})
+	req.Context(ctx)
+
+	type result struct {
+		stream io.ReadCloser
+		err    error
+	}
+	res := make(chan result, 1)
+	go func() {
+		stream, err := req.Stream()
+		res <- result{stream, err}
+	}()
+
+	select {
+	case out := <-res:
+		return out.stream, out.err
+	case <-time.After(defaultTimeout):
+		// req.Stream() may still succeed after we've given up on it; drain
+		// the result and close the stream so the connection doesn't leak.
+		go func() {
+			if out := <-res; out.stream != nil {
+				out.stream.Close()
+			}
+		}()
+		return nil, fmt.Errorf("timed out waiting for log stream `%s/%s:%s", ns, n, co)
+	}
+}
+
+// drainLogStream scans the stream line by line, forwarding lines that match
+// grep (or all lines when grep is nil) to c, and stripping the timestamp
+// openLogStream always requests unless showTimestamps asks to keep it. It
+// returns the timestamp of the last line read — zero if none could be
+// parsed — and true only if the stream ended on its own (EOF/disconnect)
+// while ctx was still live, i.e. a gap worth reconnecting over; a scan ending
+// because ctx was canceled — the caller tearing the stream down
+// intentionally — always reports false, even if the scan itself also failed
+// with an error.
+func (r *Container) drainLogStream(ctx context.Context, stream io.ReadCloser, c chan<- string, grep *regexp.Regexp, showTimestamps bool) (time.Time, bool) {
+	defer stream.Close()
+
+	var last time.Time
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		raw := scanner.Text()
+
+		ts, rest, ok := splitLogTimestamp(raw)
+		line := raw
+		if ok {
+			if ts.After(last) {
+				last = ts
+			}
+			if !showTimestamps {
+				line = rest
+			}
+		}
+
+		if grep != nil && !grep.MatchString(line) {
+			continue
+		}
+
+		select {
+		case c <- line:
+		case <-ctx.Done():
+			return last, false
+		}
+	}
+
+	return last, ctx.Err() == nil
+}
+
+// splitLogTimestamp splits a line the API server has prefixed with an
+// RFC3339Nano timestamp (requested internally by openLogStream) into the
+// timestamp and the remaining message. ok is false, and line is returned
+// unchanged, if the leading token isn't a parseable timestamp.
+func splitLogTimestamp(line string) (ts time.Time, rest string, ok bool) {
+	sp := strings.IndexByte(line, ' ')
+	if sp < 0 {
+		return time.Time{}, line, false
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, line[:sp])
+	if err != nil {
+		return time.Time{}, line, false
+	}
+
+	return ts, line[sp+1:], true
+}
+
+// ExecStreams bundles the terminal streams an interactive exec/attach
+// session reads from and writes to, plus an optional size queue to forward
+// terminal resize events to the remote shell. TerminalSizeQueue is re-exported
+// by the k8s package so this file never imports client-go directly.
+type ExecStreams struct {
+	Stdin             io.Reader
+	Stdout            io.Writer
+	Stderr            io.Writer
+	TTY               bool
+	TerminalSizeQueue k8s.TerminalSizeQueue
+}
+
+// Exec starts an interactive exec session in the given container and streams
+// stdin/stdout/stderr over the returned k8s.Executor. Callers are expected to
+// invoke Executor.Stream with the terminal streams ExecStreams bundles (as
+// client-go's remotecommand.StreamOptions) and to invoke the returned cancel
+// func to tear the session down.
+func (r *Container) Exec(ns, n, co string, cmd []string, tty bool) (k8s.Executor, context.CancelFunc, error) {
+	return runExec(ns, n, co, "Exec", func(ctx context.Context) (k8s.Executor, error) {
+		return r.Resource.(k8s.Execable).Exec(ctx, ns, n, co, cmd, tty)
+	})
+}
+
+// Attach joins an already running process in the given container, falling
+// back to a non-TTY stream when tty is false.
+func (r *Container) Attach(ns, n, co string, tty bool) (k8s.Executor, context.CancelFunc, error) {
+	return runExec(ns, n, co, "Attach", func(ctx context.Context) (k8s.Executor, error) {
+		return r.Resource.(k8s.Execable).Attach(ctx, ns, n, co, tty)
+	})
+}
+
+// runExec wraps open — an Exec or Attach call — with a cancelable context,
+// tearing the context down and logging under verb (e.g. "Exec") if open
+// fails, so Exec and Attach share one place that handles cancellation.
+func runExec(ns, n, co, verb string, open func(ctx context.Context) (k8s.Executor, error)) (k8s.Executor, context.CancelFunc, error) {
+	ctx, cancel := context.WithCancel(context.TODO())
+
+	exec, err := open(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msgf("%s canceled `%s/%s:%s", verb, ns, n, co)
+		cancel()
+		return nil, nil, err
+	}
+
+	return exec, cancel, nil
+}
+
+const (
+	// defaultStatsInterval is how often Stats polls the MetricsServer (and,
+	// when reachable, the kubelet stats/summary proxy) for a fresh sample.
+	defaultStatsInterval = 2 * time.Second
+	// defaultStatsHistory is how much sample history Stats keeps per
+	// container, regardless of how long the view has been open.
+	defaultStatsHistory = 15 * time.Minute
+	statRingSize        = int(defaultStatsHistory / defaultStatsInterval)
+)
+
+// ContainerStat is a single point-in-time resource sample for a container.
+type ContainerStat struct {
+	Timestamp time.Time
+	CPU       int64   // millicores
+	Mem       float64 // MB
+	NetRX     float64 // MB
+	NetTX     float64 // MB
+	FSUsage   float64 // MB
+}
+
+// statRing is a fixed-capacity ring buffer of ContainerStat samples.
+type statRing struct {
+	mx         sync.Mutex
+	samples    []ContainerStat
+	max        int
+	lastAccess time.Time
+}
+
+func newStatRing(max int) *statRing {
+	return &statRing{samples: make([]ContainerStat, 0, max), max: max, lastAccess: time.Now()}
+}
+
+func (s *statRing) add(st ContainerStat) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.samples = append(s.samples, st)
+	if over := len(s.samples) - s.max; over > 0 {
+		s.samples = s.samples[over:]
+	}
+	s.lastAccess = time.Now()
+}
+
+func (s *statRing) snapshot() []ContainerStat {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.lastAccess = time.Now()
+	out := make([]ContainerStat, len(s.samples))
+	copy(out, s.samples)
+
+	return out
+}
+
+func (s *statRing) idleSince(cutoff time.Time) bool {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.lastAccess.Before(cutoff)
+}
+
+var (
+	statCacheMx sync.Mutex
+	statCache   = map[string]*statRing{}
+)
+
+// healthBaseline is the restart count Healthcheck last observed while the
+// container was Ready, plus when that baseline was last touched.
+type healthBaseline struct {
+	restarts int32
+	seen     time.Time
+}
+
+var (
+	healthCacheMx sync.Mutex
+	healthCache   = map[string]healthBaseline{}
+)
+
+// cacheTTL bounds how long a container's cached stats/health history is kept
+// after it was last touched, so short-lived pods don't grow these
+// process-lifetime caches without bound.
+const cacheTTL = 30 * time.Minute
+
+var cacheSweepOnce sync.Once
+
+// startCacheSweeper launches the single background goroutine that evicts
+// stale statCache and healthCache entries. Safe to call from either cache's
+// accessor; only the first call has any effect.
+func startCacheSweeper() {
+	cacheSweepOnce.Do(func() {
+		go func() {
+			for range time.Tick(cacheTTL) {
+				evictStaleCaches()
+			}
 		}()
+	})
+}
+
+func evictStaleCaches() {
+	cutoff := time.Now().Add(-cacheTTL)
+
+	statCacheMx.Lock()
+	for key, ring := range statCache {
+		if ring.idleSince(cutoff) {
+			delete(statCache, key)
+		}
+	}
+	statCacheMx.Unlock()
+
+	healthCacheMx.Lock()
+	for key, b := range healthCache {
+		if b.seen.Before(cutoff) {
+			delete(healthCache, key)
+		}
+	}
+	healthCacheMx.Unlock()
+}
+
+// statRingFor returns the ring buffer for namespace/pod:container, creating
+// one on first use, so that switching away from and back to a container's
+// view does not reset its history. Entries idle for longer than cacheTTL are
+// evicted by the background sweeper.
+func statRingFor(key string) *statRing {
+	startCacheSweeper()
+
+	statCacheMx.Lock()
+	defer statCacheMx.Unlock()
+
+	ring, ok := statCache[key]
+	if !ok {
+		ring = newStatRing(statRingSize)
+		statCache[key] = ring
+	}
+
+	return ring
+}
+
+// statKey returns this container's ring buffer cache key.
+func (r *Container) statKey() string {
+	return r.pod.Namespace + "/" + r.pod.Name + ":" + r.instance.Name
+}
+
+// Stats streams ring-buffered CPU/MEM samples for this container at
+// defaultStatsInterval, augmented with network RX/TX and filesystem usage
+// whenever the kubelet stats/summary proxy is reachable. Samples are cached
+// per namespace/pod:container so switching views does not reset the graph;
+// the returned channel closes when ctx is canceled.
+func (r *Container) Stats(ctx context.Context) (<-chan ContainerStat, error) {
+	ring := statRingFor(r.statKey())
+	out := make(chan ContainerStat)
 
-		scanner := bufio.NewScanner(stream)
-		for scanner.Scan() {
-			c <- scanner.Text()
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(defaultStatsInterval)
+		defer ticker.Stop()
+
+		for {
 			select {
 			case <-ctx.Done():
 				return
-			default:
+			case <-ticker.C:
+				st, err := r.sampleStat()
+				if err != nil {
+					log.Warn().Err(err).Msgf("Stats sample failed `%s", r.statKey())
+					continue
+				}
+				ring.add(st)
+
+				select {
+				case out <- st:
+				case <-ctx.Done():
+					return
+				}
 			}
 		}
 	}()
 
-	return cancel, nil
+	return out, nil
+}
+
+// History returns the cached CPU and memory sparklines for this container,
+// one character per sample currently held in its ring buffer.
+func (r *Container) History() (cpu, mem string) {
+	samples := statRingFor(r.statKey()).snapshot()
+
+	cpus := make([]float64, len(samples))
+	mems := make([]float64, len(samples))
+	for i, s := range samples {
+		cpus[i] = float64(s.CPU)
+		mems[i] = s.Mem
+	}
+
+	return sparkline(cpus), sparkline(mems)
+}
+
+// sampleStat polls the MetricsServer for the current CPU/MEM usage and, when
+// the kubelet stats/summary proxy is reachable, folds in network and
+// filesystem usage too.
+func (r *Container) sampleStat() (ContainerStat, error) {
+	st := ContainerStat{Timestamp: time.Now()}
+
+	pmx, err := r.MetricsServer.FetchPodMetrics(r.pod.Namespace, r.pod.Name)
+	if err != nil {
+		return st, err
+	}
+	for _, co := range pmx.Containers {
+		if co.Name != r.instance.Name {
+			continue
+		}
+		st.CPU = co.Usage.Cpu().MilliValue()
+		st.Mem = k8s.ToMB(co.Usage.Memory().Value())
+		break
+	}
+
+	rx, tx, fs, err := r.Resource.(k8s.Statsable).ContainerSummary(r.pod.Spec.NodeName, r.pod.Namespace, r.pod.Name, r.instance.Name)
+	if err == nil {
+		st.NetRX, st.NetTX, st.FSUsage = k8s.ToMB(int64(rx)), k8s.ToMB(int64(tx)), k8s.ToMB(int64(fs))
+	}
+
+	return st, nil
+}
+
+// sparkline renders vals as a string of block characters scaled between 0
+// and the series' own maximum.
+func sparkline(vals []float64) string {
+	if len(vals) == 0 {
+		return ""
+	}
+
+	bars := []rune("▁▂▃▄▅▆▇█")
+
+	max := vals[0]
+	for _, v := range vals {
+		if v > max {
+			max = v
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+
+	out := make([]rune, len(vals))
+	for i, v := range vals {
+		idx := int(v / max * float64(len(bars)-1))
+		out[i] = bars[idx]
+	}
+
+	return string(out)
+}
+
+// Health states a container's probes can settle into, modeled on podman's
+// types.Health.
+const (
+	healthHealthy   = "healthy"
+	healthStarting  = "starting"
+	healthUnhealthy = "unhealthy"
+	healthNone      = "none"
+)
+
+// HealthState is the effective liveness/readiness state of a container,
+// synthesized from its probe specs and current ContainerStatus rather than
+// the plain yes/no of probe().
+type HealthState struct {
+	Status         string
+	FailingStreak  int
+	LastTransition time.Time
+}
+
+// String renders the health state, appending the failing streak when one is
+// in progress (e.g. "unhealthy(3)").
+func (h HealthState) String() string {
+	if h.FailingStreak > 0 {
+		return fmt.Sprintf("%s(%d)", h.Status, h.FailingStreak)
+	}
+
+	return h.Status
+}
+
+// Healthcheck synthesizes the effective liveness/readiness state for a
+// container from its ContainerStatus (Ready, Started, RestartCount) and its
+// StartupProbe/LivenessProbe/ReadinessProbe specs, so callers don't have to
+// re-derive it for every row. LastTerminationState is not consulted: the
+// failing streak counts only restarts since the container was last seen
+// Ready (see restartStreak).
+func (r *Container) Healthcheck(ns, pod, co string) (HealthState, error) {
+	spec := findContainerSpec(r.pod, co)
+	if spec == nil {
+		return HealthState{Status: healthNone}, fmt.Errorf("no container spec found for %s/%s:%s", ns, pod, co)
+	}
+
+	cs := findContainerStatus(r.pod, co)
+	if cs == nil {
+		return HealthState{Status: healthNone}, fmt.Errorf("no container status found for %s/%s:%s", ns, pod, co)
+	}
+
+	hs := HealthState{Status: healthNone, LastTransition: stateTransitionTime(cs.State, r.pod.CreationTimestamp.Time)}
+	if spec.LivenessProbe == nil && spec.ReadinessProbe == nil && spec.StartupProbe == nil {
+		return hs, nil
+	}
+
+	// Started is populated by the kubelet whenever any probes are configured,
+	// not only a StartupProbe, so a container still booting (zero restarts,
+	// not yet Ready) reports as starting rather than unhealthy.
+	if cs.Started != nil && !*cs.Started {
+		hs.Status = healthStarting
+		return hs, nil
+	}
+
+	hs.FailingStreak = restartStreak(healthKey(ns, pod, co), *cs)
+
+	switch {
+	case hs.FailingStreak > 0 || !cs.Ready:
+		hs.Status = healthUnhealthy
+	default:
+		hs.Status = healthHealthy
+	}
+
+	return hs, nil
+}
+
+// healthKey returns the restartStreak baseline's cache key.
+func healthKey(ns, pod, co string) string {
+	return ns + "/" + pod + ":" + co
+}
+
+// restartStreak derives the number of restarts since this container was last
+// observed Ready, rather than keying off the historical, sticky
+// LastTerminationState field (which Kubernetes keeps populated for the life
+// of the pod long after a container has recovered). The baseline restart
+// count is reset to the current one every time the container is seen Ready,
+// so the streak — and thus an "unhealthy" verdict — clears on recovery.
+func restartStreak(key string, cs v1.ContainerStatus) int {
+	startCacheSweeper()
+
+	healthCacheMx.Lock()
+	defer healthCacheMx.Unlock()
+
+	baseline, ok := healthCache[key]
+	if cs.Ready || !ok {
+		healthCache[key] = healthBaseline{restarts: cs.RestartCount, seen: time.Now()}
+		return 0
+	}
+	baseline.seen = time.Now()
+	healthCache[key] = baseline
+
+	if cs.RestartCount <= baseline.restarts {
+		return 0
+	}
+
+	return int(cs.RestartCount - baseline.restarts)
+}
+
+// findContainerSpec looks up a container's spec among both init and regular
+// containers.
+func findContainerSpec(pod *v1.Pod, co string) *v1.Container {
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == co {
+			return &c
+		}
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == co {
+			return &c
+		}
+	}
+
+	return nil
+}
+
+// findContainerStatus looks up a container's status among both init and
+// regular container statuses.
+func findContainerStatus(pod *v1.Pod, co string) *v1.ContainerStatus {
+	for _, s := range pod.Status.ContainerStatuses {
+		if s.Name == co {
+			return &s
+		}
+	}
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.Name == co {
+			return &s
+		}
+	}
+
+	return nil
+}
+
+// stateTransitionTime picks the timestamp of a container's most recent state
+// change, falling back to created when the state carries none.
+func stateTransitionTime(s v1.ContainerState, created time.Time) time.Time {
+	switch {
+	case s.Running != nil:
+		return s.Running.StartedAt.Time
+	case s.Terminated != nil:
+		return s.Terminated.FinishedAt.Time
+	default:
+		return created
+	}
 }
 
 // List resources for a given namespace.
@@ -133,19 +771,80 @@ func (r *Container) List(ns string) (Columnars, error) {
 	icos := r.pod.Spec.InitContainers
 	cos := r.pod.Spec.Containers
 
-	cc := make(Columnars, 0, len(icos)+len(cos))
+	cc := make(Columnars, 0, len(icos)+len(cos)+1)
+	if len(icos)+len(cos) > 1 {
+		cc = append(cc, r.newAllContainers())
+	}
 	for _, co := range icos {
+		if !r.matches(&co) {
+			continue
+		}
 		ci := r.New(co)
 		ci.(*Container).isInit = true
 		cc = append(cc, ci)
 	}
 	for _, co := range cos {
+		if !r.matches(&co) {
+			continue
+		}
 		cc = append(cc, r.New(co))
 	}
 
 	return cc, nil
 }
 
+// matches reports whether co satisfies every filter on this list, combined
+// with AND semantics. A list with no filters matches everything.
+func (r *Container) matches(co *v1.Container) bool {
+	if len(r.filters) == 0 {
+		return true
+	}
+
+	cs := findContainerStatus(r.pod, co.Name)
+	for _, f := range r.filters {
+		if !f(co, cs) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// WithFilters returns a copy of this Container list whose List additionally
+// applies ff, composed with AND semantics with any filters already set.
+func (r *Container) WithFilters(ff ...ContainerFilter) *Container {
+	co := NewContainer(r.Connection, r.MetricsServer, r.pod)
+	co.instance = r.instance
+	co.isInit = r.isInit
+	co.isAll = r.isAll
+	co.filters = append(append([]ContainerFilter{}, r.filters...), ff...)
+
+	return co
+}
+
+// WithFilterExpr parses expr (e.g. "state=running,restarts>=3") and returns
+// a copy of this Container list with the resulting filters applied, so
+// callers like the command bar can pass the raw expression straight through.
+func (r *Container) WithFilterExpr(expr string) (*Container, error) {
+	ff, err := ParseContainerFilter(r.pod, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WithFilters(ff...), nil
+}
+
+// newAllContainers builds the pseudo "--all" entry fanning in every
+// container's logs.
+func (r *Container) newAllContainers() *Container {
+	co := NewContainer(r.Connection, r.MetricsServer, r.pod)
+	co.isAll = true
+	co.instance = &v1.Container{Name: AllContainers}
+	co.path = r.namespacedName(r.pod.ObjectMeta) + ":" + AllContainers
+
+	return co
+}
+
 // Header return resource header.
 func (*Container) Header(ns string) Row {
 	hh := Row{}
@@ -160,8 +859,11 @@ func (*Container) Header(ns string) Row {
 		"RPROB",
 		"CPU",
 		"MEM",
+		"%CPU",
+		"%MEM",
 		"RCPU",
 		"RMEM",
+		"HEALTH",
 		"AGE",
 	)
 }
@@ -171,41 +873,34 @@ func (r *Container) Fields(ns string) Row {
 	ff := make(Row, 0, len(r.Header(ns)))
 	i := r.instance
 
-	var cpu int64
+	var cpu, memBytes int64
 	var mem float64
 	if r.metrics != nil {
 		for _, co := range r.metrics.Containers {
 			if co.Name == i.Name {
 				cpu = co.Usage.Cpu().MilliValue()
-				mem = k8s.ToMB(co.Usage.Memory().Value())
+				memBytes = co.Usage.Memory().Value()
+				mem = k8s.ToMB(memBytes)
 				break
 			}
 		}
 	}
 	rcpu, rmem := resources(i)
+	lcpu, lmem := limitOrRequest(i)
+	pctCPU, pctMEM := pctOf(cpu, lcpu), pctOf(memBytes, lmem)
 
-	var cs *v1.ContainerStatus
-	for _, c := range r.pod.Status.ContainerStatuses {
-		if c.Name != i.Name {
-			continue
-		}
-		cs = &c
-	}
-
-	if cs == nil {
-		for _, c := range r.pod.Status.InitContainerStatuses {
-			if c.Name != i.Name {
-				continue
-			}
-			cs = &c
-		}
-	}
+	cs := findContainerStatus(r.pod, i.Name)
 
 	ready, state, restarts := "false", MissingValue, "0"
 	if cs != nil {
 		ready, state, restarts = boolToStr(cs.Ready), toState(cs.State), strconv.Itoa(int(cs.RestartCount))
 	}
 
+	hs, err := r.Healthcheck(r.pod.Namespace, r.pod.Name, i.Name)
+	if err != nil {
+		hs = HealthState{Status: healthNone}
+	}
+
 	return append(ff,
 		i.Name,
 		i.Image,
@@ -216,8 +911,11 @@ func (r *Container) Fields(ns string) Row {
 		probe(i.ReadinessProbe),
 		ToMillicore(cpu),
 		ToMi(mem),
+		pctCPU,
+		pctMEM,
 		rcpu,
 		rmem,
+		hs.String(),
 		toAge(r.pod.CreationTimestamp),
 	)
 }
@@ -265,6 +963,37 @@ func resources(c *v1.Container) (cpu, mem string) {
 	return "0", "0"
 }
 
+// limitOrRequest returns the container's CPU millicores and memory bytes
+// ceiling, preferring Limits and falling back to Requests, for use as the
+// denominator of %CPU/%MEM.
+func limitOrRequest(c *v1.Container) (cpu, mem int64) {
+	lim, req := c.Resources.Limits, c.Resources.Requests
+
+	if q, ok := lim[v1.ResourceCPU]; ok {
+		cpu = q.MilliValue()
+	} else if q, ok := req[v1.ResourceCPU]; ok {
+		cpu = q.MilliValue()
+	}
+
+	if q, ok := lim[v1.ResourceMemory]; ok {
+		mem = q.Value()
+	} else if q, ok := req[v1.ResourceMemory]; ok {
+		mem = q.Value()
+	}
+
+	return
+}
+
+// pctOf renders used as a percentage of total, or MissingValue when total is
+// unset (no limit or request to measure against).
+func pctOf(used, total int64) string {
+	if total <= 0 {
+		return MissingValue
+	}
+
+	return strconv.Itoa(int(float64(used)/float64(total)*100)) + "%"
+}
+
 func probe(p *v1.Probe) string {
 	if p == nil {
 		return "no"
@@ -278,3 +1007,132 @@ func asMi(v int64) float64 {
 
 	return float64(v) / megaByte
 }
+
+// ----------------------------------------------------------------------------
+// Filters...
+
+// ContainerFilter reports whether a container spec (and its current status,
+// nil when the kubelet hasn't reported one yet) matches a predicate.
+type ContainerFilter func(co *v1.Container, cs *v1.ContainerStatus) bool
+
+var filterTermRX = regexp.MustCompile(`^([a-zA-Z]+)(>=|<=|~=|=)(.+)$`)
+
+// ParseContainerFilter parses a comma-separated filter expression such as
+// "state=running,restarts>=3" into a chain of ContainerFilter predicates
+// that compose with AND semantics. Supported keys: state, ready, restarts,
+// image, init, name.
+func ParseContainerFilter(pod *v1.Pod, expr string) ([]ContainerFilter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	initSet := make(map[string]bool, len(pod.Spec.InitContainers))
+	for _, c := range pod.Spec.InitContainers {
+		initSet[c.Name] = true
+	}
+
+	terms := strings.Split(expr, ",")
+	ff := make([]ContainerFilter, 0, len(terms))
+	for _, term := range terms {
+		f, err := parseContainerFilterTerm(term, initSet)
+		if err != nil {
+			return nil, err
+		}
+		ff = append(ff, f)
+	}
+
+	return ff, nil
+}
+
+func parseContainerFilterTerm(term string, initSet map[string]bool) (ContainerFilter, error) {
+	m := filterTermRX.FindStringSubmatch(strings.TrimSpace(term))
+	if m == nil {
+		return nil, fmt.Errorf("invalid container filter %q", term)
+	}
+	key, op, val := m[1], m[2], m[3]
+
+	switch key {
+	case "state":
+		want := strings.ToLower(val)
+		return func(co *v1.Container, cs *v1.ContainerStatus) bool {
+			return cs != nil && containerStateName(cs.State) == want
+		}, nil
+
+	case "ready":
+		want := val == "true"
+		return func(co *v1.Container, cs *v1.ContainerStatus) bool {
+			return cs != nil && cs.Ready == want
+		}, nil
+
+	case "restarts":
+		cmp, err := restartComparator(op, val)
+		if err != nil {
+			return nil, err
+		}
+		return func(co *v1.Container, cs *v1.ContainerStatus) bool {
+			return cs != nil && cmp(int(cs.RestartCount))
+		}, nil
+
+	case "image":
+		re, err := regexp.Compile(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image filter %q: %w", val, err)
+		}
+		return func(co *v1.Container, cs *v1.ContainerStatus) bool {
+			return re.MatchString(co.Image)
+		}, nil
+
+	case "name":
+		re, err := regexp.Compile(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name filter %q: %w", val, err)
+		}
+		return func(co *v1.Container, cs *v1.ContainerStatus) bool {
+			return re.MatchString(co.Name)
+		}, nil
+
+	case "init":
+		want := val == "true"
+		return func(co *v1.Container, cs *v1.ContainerStatus) bool {
+			return initSet[co.Name] == want
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown container filter key %q", key)
+	}
+}
+
+// restartComparator turns an operator ("=", ">=", "<=", "~=" treated as "=")
+// and an integer operand into a predicate over a container's restart count.
+func restartComparator(op, val string) (func(n int) bool, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil {
+		return nil, fmt.Errorf("invalid restarts value %q: %w", val, err)
+	}
+
+	switch op {
+	case ">=":
+		return func(got int) bool { return got >= n }, nil
+	case "<=":
+		return func(got int) bool { return got <= n }, nil
+	case "=", "~=":
+		return func(got int) bool { return got == n }, nil
+	default:
+		return nil, fmt.Errorf("unsupported restarts operator %q", op)
+	}
+}
+
+// containerStateName reports the canonical lowercase name of a container's
+// current state, used by the state= filter.
+func containerStateName(s v1.ContainerState) string {
+	switch {
+	case s.Running != nil:
+		return "running"
+	case s.Waiting != nil:
+		return "waiting"
+	case s.Terminated != nil:
+		return "terminated"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,583 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/k8s"
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// fakeExecutor is a no-op k8s.Executor double for exercising runExec without
+// a real SPDY session.
+type fakeExecutor struct{}
+
+func (fakeExecutor) Stream(_ remotecommand.StreamOptions) error { return nil }
+
+func TestParseContainerFilter(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{
+				{Name: "init-1"},
+			},
+			Containers: []v1.Container{
+				{Name: "app", Image: "nginx:1.21"},
+				{Name: "sidecar", Image: "envoy:latest"},
+			},
+		},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "app", Ready: true, RestartCount: 0, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				{Name: "sidecar", Ready: false, RestartCount: 4, State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{}}},
+			},
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "init-1", Ready: true, RestartCount: 0, State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{}}},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		expr    string
+		want    []string
+		wantErr bool
+	}{
+		"empty expr matches all": {expr: "", want: []string{"init-1", "app", "sidecar"}},
+		"state running":          {expr: "state=running", want: []string{"app"}},
+		"state waiting":          {expr: "state=waiting", want: []string{"sidecar"}},
+		"ready true":             {expr: "ready=true", want: []string{"init-1", "app"}},
+		"restarts threshold":     {expr: "restarts>=3", want: []string{"sidecar"}},
+		"image regex":            {expr: "image~=envoy", want: []string{"sidecar"}},
+		"init true":              {expr: "init=true", want: []string{"init-1"}},
+		"init false":             {expr: "init=false", want: []string{"app", "sidecar"}},
+		"name regex":             {expr: "name~=^side", want: []string{"sidecar"}},
+		"composed AND":           {expr: "state=waiting,restarts>=3", want: []string{"sidecar"}},
+		"invalid term":           {expr: "bogus", wantErr: true},
+		"unknown key":            {expr: "foo=bar", wantErr: true},
+		"bad restarts value":     {expr: "restarts>=x", wantErr: true},
+		"bad regex":              {expr: "image~=(", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ff, err := ParseContainerFilter(pod, tc.expr)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := matchingNames(pod, ff)
+			if !sameSet(got, tc.want) {
+				t.Errorf("matched %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// matchingNames runs ff (AND semantics) over every container in pod, mirroring
+// how Container.matches filters List.
+func matchingNames(pod *v1.Pod, ff []ContainerFilter) []string {
+	keep := func(co v1.Container) bool {
+		cs := findContainerStatus(pod, co.Name)
+		for _, f := range ff {
+			if !f(&co, cs) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var names []string
+	for _, co := range pod.Spec.InitContainers {
+		if keep(co) {
+			names = append(names, co.Name)
+		}
+	}
+	for _, co := range pod.Spec.Containers {
+		if keep(co) {
+			names = append(names, co.Name)
+		}
+	}
+
+	return names
+}
+
+func sameSet(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	seen := make(map[string]bool, len(got))
+	for _, g := range got {
+		seen[g] = true
+	}
+	for _, w := range want {
+		if !seen[w] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+// newHealthPod builds a single-container pod fixture for exercising
+// Healthcheck, stamping co onto both the spec and the status so
+// findContainerSpec/findContainerStatus can locate them.
+func newHealthPod(ns, name, co string, spec v1.Container, status v1.ContainerStatus) *v1.Pod {
+	spec.Name = co
+	status.Name = co
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec:       v1.PodSpec{Containers: []v1.Container{spec}},
+		Status:     v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{status}},
+	}
+}
+
+func TestHealthcheckNoProbes(t *testing.T) {
+	pod := newHealthPod("ns1", "pod1", "c1", v1.Container{}, v1.ContainerStatus{Ready: true})
+	r := &Container{pod: pod}
+
+	hs, err := r.Healthcheck("ns1", "pod1", "c1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hs.Status != healthNone {
+		t.Errorf("status = %q, want %q", hs.Status, healthNone)
+	}
+}
+
+func TestHealthcheckStartingWithoutStartupProbe(t *testing.T) {
+	spec := v1.Container{LivenessProbe: &v1.Probe{}}
+	status := v1.ContainerStatus{Ready: false, Started: boolPtr(false)}
+	pod := newHealthPod("ns2", "pod2", "c2", spec, status)
+	r := &Container{pod: pod}
+
+	hs, err := r.Healthcheck("ns2", "pod2", "c2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hs.Status != healthStarting {
+		t.Errorf("status = %q, want %q (Started is populated by the kubelet regardless of StartupProbe)", hs.Status, healthStarting)
+	}
+}
+
+func TestHealthcheckRecoveryClearsStickyStreak(t *testing.T) {
+	spec := v1.Container{LivenessProbe: &v1.Probe{}}
+	status := v1.ContainerStatus{
+		Ready:        true,
+		Started:      boolPtr(true),
+		RestartCount: 5,
+		LastTerminationState: v1.ContainerState{
+			Terminated: &v1.ContainerStateTerminated{Reason: "Error"},
+		},
+	}
+	pod := newHealthPod("ns3", "pod3", "c3", spec, status)
+	r := &Container{pod: pod}
+
+	hs, err := r.Healthcheck("ns3", "pod3", "c3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hs.Status != healthHealthy || hs.FailingStreak != 0 {
+		t.Errorf("got status=%q streak=%d, want healthy/0 — a Ready container with an old crash still recorded in LastTerminationState must not be permanently unhealthy", hs.Status, hs.FailingStreak)
+	}
+}
+
+func TestHealthcheckStreakTracksRestartDelta(t *testing.T) {
+	spec := v1.Container{LivenessProbe: &v1.Probe{}}
+
+	good := newHealthPod("ns4", "pod4", "c4", spec, v1.ContainerStatus{Ready: true, Started: boolPtr(true), RestartCount: 2})
+	if _, err := (&Container{pod: good}).Healthcheck("ns4", "pod4", "c4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	crashing := newHealthPod("ns4", "pod4", "c4", spec, v1.ContainerStatus{Ready: false, Started: boolPtr(true), RestartCount: 5})
+	hs, err := (&Container{pod: crashing}).Healthcheck("ns4", "pod4", "c4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hs.Status != healthUnhealthy {
+		t.Errorf("status = %q, want %q", hs.Status, healthUnhealthy)
+	}
+	if hs.FailingStreak != 3 {
+		t.Errorf("failing streak = %d, want 3 (5 restarts - baseline of 2 seen at the last Ready observation)", hs.FailingStreak)
+	}
+}
+
+// closedLine returns a ready-to-drain single-line sub-stream, as a stand-in
+// for what Logs hands fanInLogs for one container.
+func closedLine(line string) <-chan string {
+	sub := make(chan string, 1)
+	sub <- line
+	close(sub)
+	return sub
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline(nil); got != "" {
+		t.Errorf("empty series = %q, want empty string", got)
+	}
+
+	bars := []rune("▁▂▃▄▅▆▇█")
+
+	if got := sparkline([]float64{0, 0, 0}); got != strings.Repeat(string(bars[0]), 3) {
+		t.Errorf("all-zero series = %q, want every sample at the floor bar", got)
+	}
+
+	got := []rune(sparkline([]float64{0, 50, 100}))
+	if len(got) != 3 {
+		t.Fatalf("len = %d, want 3", len(got))
+	}
+	if got[0] != bars[0] {
+		t.Errorf("first bar = %q, want floor bar %q", string(got[0]), string(bars[0]))
+	}
+	if got[2] != bars[len(bars)-1] {
+		t.Errorf("last bar = %q, want ceiling bar %q (scaled to the series' own max)", string(got[2]), string(bars[len(bars)-1]))
+	}
+}
+
+func TestLimitOrRequest(t *testing.T) {
+	tests := map[string]struct {
+		c           v1.Container
+		wantCPU     int64
+		wantMemByte int64
+	}{
+		"no resources": {c: v1.Container{}},
+		"requests only": {
+			c: v1.Container{Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse("250m"), v1.ResourceMemory: apires.MustParse("64Mi")},
+			}},
+			wantCPU: 250, wantMemByte: 64 * 1024 * 1024,
+		},
+		"limits preferred over requests": {
+			c: v1.Container{Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse("250m"), v1.ResourceMemory: apires.MustParse("64Mi")},
+				Limits:   v1.ResourceList{v1.ResourceCPU: apires.MustParse("500m"), v1.ResourceMemory: apires.MustParse("128Mi")},
+			}},
+			wantCPU: 500, wantMemByte: 128 * 1024 * 1024,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cpu, mem := limitOrRequest(&tc.c)
+			if cpu != tc.wantCPU {
+				t.Errorf("cpu = %d, want %d", cpu, tc.wantCPU)
+			}
+			if mem != tc.wantMemByte {
+				t.Errorf("mem = %d, want %d", mem, tc.wantMemByte)
+			}
+		})
+	}
+}
+
+func TestPctOf(t *testing.T) {
+	tests := map[string]struct {
+		used, total int64
+		want        string
+	}{
+		"no ceiling":       {used: 50, total: 0, want: MissingValue},
+		"negative ceiling": {used: 50, total: -1, want: MissingValue},
+		"quarter":          {used: 50, total: 200, want: "25%"},
+		"all used":         {used: 100, total: 100, want: "100%"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := pctOf(tc.used, tc.total); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStatRingCapsAtMax(t *testing.T) {
+	ring := newStatRing(3)
+	for i := 0; i < 5; i++ {
+		ring.add(ContainerStat{CPU: int64(i)})
+	}
+
+	got := ring.snapshot()
+	if len(got) != 3 {
+		t.Fatalf("len = %d, want 3 (ring should evict the oldest samples, not grow)", len(got))
+	}
+	for i, want := range []int64{2, 3, 4} {
+		if got[i].CPU != want {
+			t.Errorf("sample %d CPU = %d, want %d", i, got[i].CPU, want)
+		}
+	}
+}
+
+func TestStatRingIdleSince(t *testing.T) {
+	ring := newStatRing(3)
+
+	if ring.idleSince(time.Now().Add(-time.Minute)) {
+		t.Error("freshly created ring reported idle since a minute ago")
+	}
+
+	ring.lastAccess = time.Now().Add(-time.Hour)
+	if !ring.idleSince(time.Now().Add(-time.Minute)) {
+		t.Error("ring last touched an hour ago should report idle since a minute ago")
+	}
+
+	ring.snapshot()
+	if ring.idleSince(time.Now().Add(-time.Minute)) {
+		t.Error("snapshot should refresh lastAccess, clearing idleness")
+	}
+}
+
+func TestEvictStaleCachesRemovesOnlyIdleEntries(t *testing.T) {
+	freshKey, staleKey := "evict-test/fresh:co", "evict-test/stale:co"
+
+	fresh, stale := newStatRing(3), newStatRing(3)
+	stale.lastAccess = time.Now().Add(-2 * cacheTTL)
+
+	statCacheMx.Lock()
+	statCache[freshKey] = fresh
+	statCache[staleKey] = stale
+	statCacheMx.Unlock()
+
+	evictStaleCaches()
+
+	statCacheMx.Lock()
+	_, freshOK := statCache[freshKey]
+	_, staleOK := statCache[staleKey]
+	statCacheMx.Unlock()
+
+	if !freshOK {
+		t.Error("expected the freshly touched ring to survive eviction")
+	}
+	if staleOK {
+		t.Error("expected the ring idle for longer than cacheTTL to be evicted")
+	}
+}
+
+func TestRunExecCancelsContextAndReturnsNilCancelOnError(t *testing.T) {
+	var sawCanceled bool
+	var ctxSeen context.Context
+	_, cancel, err := runExec("ns", "pod", "co", "Exec", func(ctx context.Context) (k8s.Executor, error) {
+		ctxSeen = ctx
+		sawCanceled = ctx.Err() != nil
+		return nil, fmt.Errorf("boom")
+	})
+
+	if sawCanceled {
+		t.Fatal("context was already canceled before open ran")
+	}
+	if ctxSeen.Err() == nil {
+		t.Error("expected runExec to cancel open's context once open failed")
+	}
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if cancel != nil {
+		t.Fatal("expected a nil cancel func on error")
+	}
+}
+
+func TestRunExecReturnsLiveCancelOnSuccess(t *testing.T) {
+	var ctxSeen context.Context
+	exec, cancel, err := runExec("ns", "pod", "co", "Attach", func(ctx context.Context) (k8s.Executor, error) {
+		ctxSeen = ctx
+		return fakeExecutor{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exec == nil {
+		t.Fatal("expected a non-nil executor")
+	}
+	if ctxSeen.Err() != nil {
+		t.Fatal("expected open to receive a live context")
+	}
+
+	cancel()
+	if ctxSeen.Err() == nil {
+		t.Error("expected the returned cancel func to cancel open's context")
+	}
+}
+
+func TestSplitLogTimestamp(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	line := ts.Format(time.RFC3339Nano) + " hello world"
+
+	got, rest, ok := splitLogTimestamp(line)
+	if !ok {
+		t.Fatal("expected ok=true for a timestamped line")
+	}
+	if !got.Equal(ts) {
+		t.Errorf("timestamp = %v, want %v", got, ts)
+	}
+	if rest != "hello world" {
+		t.Errorf("rest = %q, want %q", rest, "hello world")
+	}
+
+	if _, _, ok := splitLogTimestamp("no timestamp here"); ok {
+		t.Error("expected ok=false for a line with no parseable timestamp")
+	}
+}
+
+func TestDrainLogStreamStripsTimestampsByDefault(t *testing.T) {
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Second)
+	body := t1.Format(time.RFC3339Nano) + " first\n" + t2.Format(time.RFC3339Nano) + " second\n"
+
+	r := &Container{}
+	c := make(chan string, 2)
+	last, clean := r.drainLogStream(context.Background(), io.NopCloser(strings.NewReader(body)), c, nil, false)
+	close(c)
+
+	var got []string
+	for line := range c {
+		got = append(got, line)
+	}
+	want := []string{"first", "second"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !clean {
+		t.Error("expected a clean EOF")
+	}
+	if !last.Equal(t2) {
+		t.Errorf("last = %v, want %v (the later of the two lines' timestamps)", last, t2)
+	}
+}
+
+func TestDrainLogStreamKeepsTimestampsWhenRequested(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	line := ts.Format(time.RFC3339Nano) + " hello"
+
+	r := &Container{}
+	c := make(chan string, 1)
+	_, clean := r.drainLogStream(context.Background(), io.NopCloser(strings.NewReader(line+"\n")), c, nil, true)
+	close(c)
+
+	if !clean {
+		t.Fatal("expected a clean EOF")
+	}
+	if got := <-c; got != line {
+		t.Errorf("got %q, want %q (timestamp preserved)", got, line)
+	}
+}
+
+func TestDrainLogStreamAppliesGrep(t *testing.T) {
+	body := "keep this\nskip that\nkeep another\n"
+	grep := regexp.MustCompile(`^keep`)
+
+	r := &Container{}
+	c := make(chan string, 2)
+	_, clean := r.drainLogStream(context.Background(), io.NopCloser(strings.NewReader(body)), c, grep, false)
+	close(c)
+
+	if !clean {
+		t.Fatal("expected a clean EOF")
+	}
+
+	var got []string
+	for line := range c {
+		got = append(got, line)
+	}
+	want := []string{"keep this", "keep another"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDrainLogStreamCancelIsUnclean(t *testing.T) {
+	body := "line one\nline two\nline three\n"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &Container{}
+	c := make(chan string) // unbuffered and never drained, so the first send blocks on ctx.Done()
+	_, clean := r.drainLogStream(ctx, io.NopCloser(strings.NewReader(body)), c, nil, false)
+
+	if clean {
+		t.Error("expected a canceled drain to report unclean, regardless of whether the scan itself also reached EOF")
+	}
+}
+
+func TestFanInLogsAggregatesAndClosesOnCompletion(t *testing.T) {
+	c := make(chan string)
+
+	open := func(co string) (<-chan string, context.CancelFunc, error) {
+		return closedLine("hello from " + co), func() {}, nil
+	}
+
+	cancel, err := fanInLogs([]string{"init", "app"}, open, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cancel()
+
+	got := map[string]bool{}
+	for line := range c {
+		got[line] = true
+	}
+
+	for _, want := range []string{"[init] hello from init", "[app] hello from app"} {
+		if !got[want] {
+			t.Errorf("missing line %q in %v", want, got)
+		}
+	}
+}
+
+func TestFanInLogsSkipsContainersThatFailToOpen(t *testing.T) {
+	c := make(chan string)
+
+	open := func(co string) (<-chan string, context.CancelFunc, error) {
+		if co == "bad" {
+			return nil, nil, fmt.Errorf("boom")
+		}
+		return closedLine("ok"), func() {}, nil
+	}
+
+	cancel, err := fanInLogs([]string{"bad", "good"}, open, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cancel()
+
+	var lines []string
+	for line := range c {
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 1 || lines[0] != "[good] ok" {
+		t.Errorf("got %v, want exactly [\"[good] ok\"]", lines)
+	}
+}
+
+func TestFanInLogsClosesWhenNoContainersOpen(t *testing.T) {
+	c := make(chan string)
+
+	open := func(co string) (<-chan string, context.CancelFunc, error) {
+		return nil, nil, fmt.Errorf("boom")
+	}
+
+	cancel, err := fanInLogs([]string{"bad"}, open, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cancel()
+
+	if _, ok := <-c; ok {
+		t.Fatal("expected c to be closed with no lines")
+	}
+}